@@ -5,6 +5,8 @@
 package gocui
 
 import (
+	"time"
+
 	"github.com/go-errors/errors"
 
 	"github.com/mattn/go-runewidth"
@@ -33,6 +35,8 @@ var DefaultEditor Editor = EditorFunc(simpleEditor)
 // simpleEditor is used as the default gocui editor.
 func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
 	switch {
+	case (key == KeyBackspace || key == KeyBackspace2) && mod == ModAlt:
+		v.EditDeleteWordLeft()
 	case key == KeyBackspace || key == KeyBackspace2:
 		v.EditDelete(true)
 	case key == KeyDelete:
@@ -57,6 +61,26 @@ func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
 		v.EditGotoToStartOfLine()
 	case key == KeyCtrlE:
 		v.EditGotoToEndOfLine()
+	case key == KeyCtrlZ:
+		v.Undo()
+	case mod == ModAlt && ch == 'z':
+		// Ctrl+Shift+Z isn't representable over the terminal, and Ctrl+Y is
+		// taken by EditYank below, so redo lives on Alt+Z instead.
+		v.Redo()
+	case key == KeyCtrlW:
+		v.EditDeleteWordLeft()
+	case mod == ModAlt && ch == 'd':
+		v.EditDeleteWordRight()
+	case mod == ModAlt && ch == 'b':
+		v.EditMoveWordLeft()
+	case mod == ModAlt && ch == 'f':
+		v.EditMoveWordRight()
+	case key == KeyCtrlK:
+		v.EditKillToEndOfLine()
+	case key == KeyCtrlY:
+		v.EditYank()
+	case mod == ModAlt && ch == 'y':
+		v.EditYankPop()
 	default:
 		v.EditWrite(ch)
 	}
@@ -67,6 +91,13 @@ func (v *View) EditWrite(ch rune) {
 	w := runewidth.RuneWidth(ch)
 	v.writeRune(v.wcx, v.wcy, ch)
 	v.moveCursor(w, 0, true)
+
+	// Overwrite mode usually replaces a rune in place, but writeRune still
+	// grows the line when the cursor is at or past its end, so a line can
+	// exceed WrapWidth in Overwrite mode too; always check.
+	if v.WrapWidth > 0 {
+		v.wrapCurrentLine()
+	}
 }
 
 // EditDeleteToStartOfLine is the equivalent of pressing ctrl+U in your terminal, it deletes to the end of the line. Or if you are already at the start of the line, it deletes the newline character
@@ -327,7 +358,9 @@ func (v *View) moveCursor(dx, dy int, writeMode bool) {
 // governed by the value of View.overwrite.
 func (v *View) writeRune(x, y int, ch rune) error {
 	v.tainted = true
+	v.invalidateSearch()
 
+	origX, origY := x, y
 	x, y, err := v.realPosition(x, y)
 	if err != nil {
 		return err
@@ -355,12 +388,67 @@ func (v *View) writeRune(x, y int, ch rune) error {
 	if !v.Overwrite || (v.Overwrite && x >= olen-1) {
 		copy(v.lines[y][x+1:], v.lines[y][x:])
 	}
+	var overwritten []cell
+	if v.Overwrite && x < olen {
+		overwritten = []cell{v.lines[y][x]}
+	}
 	v.lines[y][x] = cell{
 		fgColor: v.FgColor,
 		bgColor: v.BgColor,
 		chr:     ch,
 	}
 
+	if !v.suppressUndo {
+		// an overwrite is recorded as a delete of the old rune followed by an
+		// insert of the new one, so undoing both restores the old rune
+		if overwritten != nil {
+			v.pushUndo(&editRecord{
+				kind:  editActionDelete,
+				x:     origX,
+				y:     origY,
+				cells: overwritten,
+				at:    time.Now(),
+			})
+		}
+		v.pushUndo(&editRecord{
+			kind:  editActionInsert,
+			x:     origX,
+			y:     origY,
+			cells: []cell{v.lines[y][x]},
+			at:    time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// insertCell inserts an existing cell into the view's internal buffer at
+// (x, y), shifting subsequent cells right. Unlike writeRune it preserves the
+// cell's original colors instead of stamping the view's current ones, which
+// lets Undo restore deleted text exactly as it was.
+func (v *View) insertCell(x, y int, c cell) error {
+	v.tainted = true
+	v.invalidateSearch()
+
+	x, y, err := v.realPosition(x, y)
+	if err != nil {
+		return err
+	}
+
+	if x < 0 || y < 0 {
+		return errors.New("invalid point")
+	}
+
+	if y >= len(v.lines) {
+		s := make([][]cell, y-len(v.lines)+1)
+		v.lines = append(v.lines, s...)
+	}
+
+	s := make([]cell, 1)
+	v.lines[y] = append(v.lines[y], s...)
+	copy(v.lines[y][x+1:], v.lines[y][x:])
+	v.lines[y][x] = c
+
 	return nil
 }
 
@@ -369,7 +457,9 @@ func (v *View) writeRune(x, y int, ch rune) error {
 // returns the amount of columns that where removed.
 func (v *View) deleteRune(x, y int) (int, error) {
 	v.tainted = true
+	v.invalidateSearch()
 
+	origX, origY := x, y
 	x, y, err := v.realPosition(x, y)
 	if err != nil {
 		return 0, err
@@ -384,7 +474,17 @@ func (v *View) deleteRune(x, y int) (int, error) {
 		w := runewidth.RuneWidth(v.lines[y][i].chr)
 		tw += w
 		if tw > x {
+			removed := v.lines[y][i]
 			v.lines[y] = append(v.lines[y][:i], v.lines[y][i+1:]...)
+			if !v.suppressUndo {
+				v.pushUndo(&editRecord{
+					kind:  editActionDelete,
+					x:     origX,
+					y:     origY,
+					cells: []cell{removed},
+					at:    time.Now(),
+				})
+			}
 			return w, nil
 		}
 
@@ -396,7 +496,9 @@ func (v *View) deleteRune(x, y int) (int, error) {
 // mergeLines merges the lines "y" and "y+1" if possible.
 func (v *View) mergeLines(y int) error {
 	v.tainted = true
+	v.invalidateSearch()
 
+	origY := y
 	_, y, err := v.realPosition(0, y)
 	if err != nil {
 		return err
@@ -407,8 +509,18 @@ func (v *View) mergeLines(y int) error {
 	}
 
 	if y < len(v.lines)-1 { // otherwise we don't need to merge anything
+		splitX := len(v.lines[y])
 		v.lines[y] = append(v.lines[y], v.lines[y+1]...)
 		v.lines = append(v.lines[:y+1], v.lines[y+2:]...)
+
+		if !v.suppressUndo {
+			v.pushUndo(&editRecord{
+				kind: editActionMergeLine,
+				x:    splitX,
+				y:    origY,
+				at:   time.Now(),
+			})
+		}
 	}
 	return nil
 }
@@ -417,7 +529,9 @@ func (v *View) mergeLines(y int) error {
 // to the point (x, y).
 func (v *View) breakLine(x, y int) error {
 	v.tainted = true
+	v.invalidateSearch()
 
+	origX, origY := x, y
 	x, y, err := v.realPosition(x, y)
 	if err != nil {
 		return err
@@ -443,5 +557,14 @@ func (v *View) breakLine(x, y int) error {
 	copy(lines, v.lines[:y])
 	copy(lines[y+2:], v.lines[y+1:])
 	v.lines = lines
+
+	if !v.suppressUndo {
+		v.pushUndo(&editRecord{
+			kind: editActionBreakLine,
+			x:    origX,
+			y:    origY,
+			at:   time.Now(),
+		})
+	}
 	return nil
 }
@@ -0,0 +1,83 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// Key represents special keys or keys combinations.
+type Key int
+
+// Modifier allows to define special keys combinations. It is passed to an
+// Editor as a separate argument alongside the Key or rune it modifies.
+type Modifier int
+
+const (
+	ModNone Modifier = iota
+	ModAlt
+)
+
+// Keybindings, special keys.
+const (
+	KeyCtrlA Key = iota
+	KeyCtrlB
+	KeyCtrlC
+	KeyCtrlD
+	KeyCtrlE
+	KeyCtrlF
+	KeyCtrlG
+	KeyBackspace
+	KeyTab
+	KeyCtrlJ
+	KeyCtrlK
+	KeyCtrlL
+	KeyEnter
+	KeyCtrlN
+	KeyCtrlO
+	KeyCtrlP
+	KeyCtrlQ
+	KeyCtrlR
+	KeyCtrlS
+	KeyCtrlT
+	KeyCtrlU
+	KeyCtrlV
+	KeyCtrlW
+	KeyCtrlX
+	KeyCtrlY
+	KeyCtrlZ
+	KeyEsc
+	KeyCtrlSlash
+	KeyCtrlRsqBracket
+	KeyCtrl6
+	KeyCtrlUnderscore
+	KeySpace
+	KeyBackspace2
+	KeyCtrl2
+	KeyCtrl3
+	KeyCtrl4
+	KeyCtrl5
+	KeyCtrl7
+	KeyCtrl8
+
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyInsert
+	KeyDelete
+	KeyHome
+	KeyEnd
+	KeyPgup
+	KeyPgdn
+	KeyArrowUp
+	KeyArrowDown
+	KeyArrowLeft
+	KeyArrowRight
+)
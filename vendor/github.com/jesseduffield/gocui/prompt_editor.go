@@ -0,0 +1,221 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Completer proposes completions for the word at pos in line. head and tail
+// are the parts of the line before/after the word being completed;
+// completions are candidate replacements for that word.
+type Completer func(line string, pos int) (head string, completions []string, tail string)
+
+// PromptEditor is an Editor suited to single-line input fields such as
+// search bars, commit-message subjects, or filter inputs. It adds line
+// history (Up/Down, Ctrl+R reverse search) and Tab-completion via
+// View.Completer on top of simpleEditor's single-line behavior. Assign it
+// to View.Editor and set View.HistoryEnabled to opt a view into it.
+type PromptEditor struct{}
+
+// NewPromptEditor returns a PromptEditor ready to be assigned to
+// View.Editor.
+func NewPromptEditor() *PromptEditor {
+	return &PromptEditor{}
+}
+
+// Edit implements the Editor interface.
+func (e *PromptEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	if !v.HistoryEnabled {
+		simpleEditor(v, key, ch, mod)
+		return
+	}
+
+	if v.historySearchActive {
+		switch {
+		case key == KeyCtrlR:
+			v.advanceHistorySearch()
+		case key == KeyEsc:
+			v.endHistorySearch(false)
+		case key == KeyEnter:
+			v.endHistorySearch(true)
+		case key == KeyBackspace || key == KeyBackspace2:
+			v.historySearchQuery = trimLastRune(v.historySearchQuery)
+			v.historySearchPos = len(v.History)
+			v.refreshHistorySearch()
+		case ch != 0:
+			v.historySearchQuery += string(ch)
+			v.historySearchPos = len(v.History)
+			v.refreshHistorySearch()
+		}
+		return
+	}
+
+	switch {
+	case key == KeyCtrlR:
+		v.beginHistorySearch()
+	case key == KeyArrowUp:
+		v.HistoryPrev()
+	case key == KeyArrowDown:
+		v.HistoryNext()
+	case key == KeyTab:
+		v.handleCompletion()
+	case key == KeyEnter:
+		v.PushHistory(v.currentLine())
+		v.setLine("")
+	default:
+		v.completions = nil
+		simpleEditor(v, key, ch, mod)
+	}
+}
+
+// PushHistory appends line to the view's history, used by HistoryPrev,
+// HistoryNext, and reverse search. Consecutive duplicate entries are
+// collapsed into one.
+func (v *View) PushHistory(line string) {
+	if !v.HistoryEnabled || line == "" {
+		return
+	}
+
+	if n := len(v.History); n > 0 && v.History[n-1] == line {
+		v.historyPos = len(v.History)
+		return
+	}
+
+	v.History = append(v.History, line)
+	v.historyPos = len(v.History)
+}
+
+// HistoryPrev replaces the buffer with the previous history entry, if any.
+func (v *View) HistoryPrev() {
+	if len(v.History) == 0 || v.historyPos == 0 {
+		return
+	}
+	v.historyPos--
+	v.setLine(v.History[v.historyPos])
+}
+
+// HistoryNext replaces the buffer with the next history entry, or clears it
+// when already at the most recent one.
+func (v *View) HistoryNext() {
+	if len(v.History) == 0 {
+		return
+	}
+	if v.historyPos >= len(v.History)-1 {
+		v.historyPos = len(v.History)
+		v.setLine("")
+		return
+	}
+	v.historyPos++
+	v.setLine(v.History[v.historyPos])
+}
+
+// beginHistorySearch enters reverse-incremental search mode (Ctrl+R),
+// starting from the most recent history entry. It remembers the buffer's
+// current content so a cancelled search can restore it.
+func (v *View) beginHistorySearch() {
+	v.historySearchActive = true
+	v.historySearchQuery = ""
+	v.historySearchPos = len(v.History)
+	v.historySearchPrev = v.currentLine()
+}
+
+// advanceHistorySearch moves a search already in progress to the next
+// older match for the current query.
+func (v *View) advanceHistorySearch() {
+	v.refreshHistorySearch()
+}
+
+// refreshHistorySearch scans History backwards from historySearchPos for an
+// entry containing historySearchQuery, installing the first match it finds
+// as the buffer and leaving historySearchPos there so the next Ctrl+R
+// continues from this point.
+func (v *View) refreshHistorySearch() {
+	for i := v.historySearchPos - 1; i >= 0; i-- {
+		if strings.Contains(v.History[i], v.historySearchQuery) {
+			v.historySearchPos = i
+			v.setLine(v.History[i])
+			return
+		}
+	}
+}
+
+// endHistorySearch leaves reverse-search mode, keeping the matched buffer
+// when accept is true or restoring whatever was in the buffer before the
+// search began otherwise (e.g. on Esc).
+func (v *View) endHistorySearch(accept bool) {
+	v.historySearchActive = false
+	v.historySearchQuery = ""
+	if !accept {
+		v.setLine(v.historySearchPrev)
+	}
+	v.historySearchPrev = ""
+}
+
+// handleCompletion implements Tab-completion: the first Tab asks
+// View.Completer for candidates and inserts the first one; repeated Tabs
+// cycle through the rest, and the candidates are mirrored into
+// View.CompletionView when one is set.
+func (v *View) handleCompletion() {
+	if v.Completer == nil {
+		return
+	}
+
+	if len(v.completions) > 0 {
+		v.completionPos = (v.completionPos + 1) % len(v.completions)
+		v.applyCompletion()
+		return
+	}
+
+	head, completions, tail := v.Completer(v.currentLine(), v.wcx)
+	if len(completions) == 0 {
+		return
+	}
+
+	v.completionHead = head
+	v.completionTail = tail
+	v.completions = completions
+	v.completionPos = 0
+	v.applyCompletion()
+
+	if v.CompletionView != nil {
+		v.CompletionView.Clear()
+		for _, c := range completions {
+			fmt.Fprintln(v.CompletionView, c)
+		}
+	}
+}
+
+func (v *View) applyCompletion() {
+	v.setLine(v.completionHead + v.completions[v.completionPos] + v.completionTail)
+}
+
+// setLine replaces the entire (single-line) buffer with s and moves the
+// cursor to the end of it.
+func (v *View) setLine(s string) {
+	v.lines = [][]cell{nil}
+	_ = v.setWriteCursor(0, 0)
+	for _, ch := range s {
+		v.EditWrite(ch)
+	}
+}
+
+// currentLine returns the contents of line 0 of the buffer as a string,
+// which is all PromptEditor ever needs since it's single-line.
+func (v *View) currentLine() string {
+	if len(v.lines) == 0 {
+		return ""
+	}
+	return cellsToString(v.lines[0])
+}
+
+func trimLastRune(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(r[:len(r)-1])
+}
@@ -0,0 +1,65 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+// keyNone stands in for "no special key" on a plain rune keystroke in
+// these tests; it must not collide with any of the KeyCtrl*/KeyArrow*
+// constants, unlike 0 which aliases KeyCtrlA.
+const keyNone Key = -1
+
+func newTestPromptView() *View {
+	v := newTestView()
+	v.HistoryEnabled = true
+	return v
+}
+
+// sendPromptKeys feeds each rune of s through e as consecutive normal
+// keystrokes, syncing viewLines after each the same way the real render
+// loop would before the next one arrives.
+func sendPromptKeys(e *PromptEditor, v *View, s string) {
+	for _, ch := range s {
+		e.Edit(v, keyNone, ch, 0)
+		syncViewLines(v)
+	}
+}
+
+func TestPromptEditorEnterClearsLineWithoutNUL(t *testing.T) {
+	v := newTestPromptView()
+	e := NewPromptEditor()
+
+	sendPromptKeys(e, v, "hello")
+	e.Edit(v, KeyEnter, 0, 0)
+	syncViewLines(v)
+
+	if got := v.currentLine(); got != "" {
+		t.Fatalf("line after Enter: got %q, want empty", got)
+	}
+	if len(v.History) != 1 || v.History[0] != "hello" {
+		t.Fatalf("history after Enter: got %v, want [hello]", v.History)
+	}
+}
+
+func TestPromptEditorEscRestoresPreSearchContent(t *testing.T) {
+	v := newTestPromptView()
+	v.History = []string{"git commit", "git push"}
+	e := NewPromptEditor()
+
+	sendPromptKeys(e, v, "partial")
+
+	e.Edit(v, KeyCtrlR, 0, 0)
+	e.Edit(v, keyNone, 'g', 0)
+	syncViewLines(v)
+	if got := v.currentLine(); got != "git push" {
+		t.Fatalf("line mid-search: got %q, want %q", got, "git push")
+	}
+
+	e.Edit(v, KeyEsc, 0, 0)
+	syncViewLines(v)
+	if got := v.currentLine(); got != "partial" {
+		t.Fatalf("line after Esc: got %q, want %q", got, "partial")
+	}
+}
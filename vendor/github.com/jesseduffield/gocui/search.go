@@ -0,0 +1,257 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Match is a single search hit within a View's buffer, given as a
+// half-open span [XStart, XEnd) on logical line Y.
+type Match struct {
+	Y      int
+	XStart int
+	XEnd   int
+}
+
+// SearchOptions controls how View.Search interprets its pattern.
+type SearchOptions struct {
+	CaseSensitive bool
+	WholeWord     bool
+	Regex         bool
+}
+
+// Search scans the View's buffer for pattern and records the results so
+// they can be stepped through with NextMatch/PrevMatch. The current match
+// is highlighted using SelFgColor/SelBgColor by the renderer the same way
+// the active selection is. It replaces any previous search.
+func (v *View) Search(pattern string, opts SearchOptions) ([]Match, error) {
+	v.ClearSearch()
+	if pattern == "" {
+		return nil, nil
+	}
+
+	expr := pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !opts.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for y, line := range v.lines {
+		s := cellsToString(line)
+		for _, loc := range re.FindAllStringIndex(s, -1) {
+			// loc holds byte offsets into s; convert them to cell indices.
+			start := utf8.RuneCountInString(s[:loc[0]])
+			end := start + utf8.RuneCountInString(s[loc[0]:loc[1]])
+			matches = append(matches, Match{Y: y, XStart: start, XEnd: end})
+		}
+	}
+
+	v.searchMatches = matches
+	v.searchPattern = pattern
+	v.searchOpts = opts
+	v.searchPos = -1
+	return matches, nil
+}
+
+// NextMatch advances to the next search match after the current position,
+// wrapping around, scrolls it into view, and returns it. It reports false
+// if there is no active search.
+func (v *View) NextMatch() (Match, bool) {
+	if len(v.searchMatches) == 0 {
+		return Match{}, false
+	}
+	v.searchPos = (v.searchPos + 1) % len(v.searchMatches)
+	m := v.searchMatches[v.searchPos]
+	v.scrollToMatch(m)
+	return m, true
+}
+
+// PrevMatch is the reverse of NextMatch.
+func (v *View) PrevMatch() (Match, bool) {
+	if len(v.searchMatches) == 0 {
+		return Match{}, false
+	}
+	v.searchPos--
+	if v.searchPos < 0 {
+		v.searchPos = len(v.searchMatches) - 1
+	}
+	m := v.searchMatches[v.searchPos]
+	v.scrollToMatch(m)
+	return m, true
+}
+
+// ClearSearch discards the active search and its highlighting.
+func (v *View) ClearSearch() {
+	v.searchMatches = nil
+	v.searchPattern = ""
+	v.searchPos = -1
+}
+
+// scrollToMatch adjusts ox/oy, the minimum amount needed, so that m is
+// visible within the view.
+func (v *View) scrollToMatch(m Match) {
+	maxX, maxY := v.Size()
+
+	if m.Y < v.oy {
+		v.oy = m.Y
+	} else if m.Y >= v.oy+maxY {
+		v.oy = m.Y - maxY + 1
+	}
+	if v.oy < 0 {
+		v.oy = 0
+	}
+
+	if m.XStart < v.ox {
+		v.ox = m.XStart
+	} else if m.XEnd > v.ox+maxX {
+		v.ox = m.XEnd - maxX
+	}
+	if v.ox < 0 {
+		v.ox = 0
+	}
+}
+
+// invalidateSearch drops the active search once the buffer it was computed
+// against has been mutated by one of the low-level edit primitives, so
+// stale offsets are never highlighted. Callers that want search to survive
+// a full-content replace (e.g. a read-only panel re-rendering) should call
+// Search again with the same pattern rather than relying on this.
+func (v *View) invalidateSearch() {
+	if len(v.searchMatches) > 0 {
+		v.ClearSearch()
+	}
+}
+
+// IncrementalSearchEditor wraps another Editor, adding Ctrl+F (search
+// forward) and Ctrl+R (search backward) over the view's own buffer. While a
+// search is active, keystrokes extend or shrink the pattern instead of
+// reaching the wrapped editor; Enter accepts the current match and leaves
+// the highlighting in place, Esc cancels and restores the view to where the
+// search began.
+type IncrementalSearchEditor struct {
+	Wrapped Editor
+
+	// PromptView, when set, is cleared and filled with the live pattern as
+	// the user types, for rendering as a companion popup view.
+	PromptView *View
+
+	active  bool
+	forward bool
+	query   string
+
+	// origOx/origOy/origWcx/origWcy are the view's scroll position and
+	// cursor as they were when the search began, restored by cancel so Esc
+	// leaves the view exactly where it was.
+	origOx, origOy   int
+	origWcx, origWcy int
+}
+
+// NewIncrementalSearchEditor wraps wrapped with incremental search.
+func NewIncrementalSearchEditor(wrapped Editor) *IncrementalSearchEditor {
+	return &IncrementalSearchEditor{Wrapped: wrapped}
+}
+
+// Edit implements the Editor interface.
+func (e *IncrementalSearchEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	if e.active {
+		switch {
+		case key == KeyEsc:
+			e.cancel(v)
+		case key == KeyEnter:
+			e.accept()
+		case key == KeyBackspace || key == KeyBackspace2:
+			e.setQuery(v, trimLastRune(e.query))
+		case key == KeyCtrlF:
+			e.forward = true
+			v.NextMatch()
+		case key == KeyCtrlR:
+			e.forward = false
+			v.PrevMatch()
+		case ch != 0:
+			e.setQuery(v, e.query+string(ch))
+		}
+		return
+	}
+
+	switch {
+	case key == KeyCtrlF:
+		e.begin(v, true)
+	case key == KeyCtrlR:
+		e.begin(v, false)
+	default:
+		e.Wrapped.Edit(v, key, ch, mod)
+	}
+}
+
+func (e *IncrementalSearchEditor) begin(v *View, forward bool) {
+	e.active = true
+	e.forward = forward
+	e.query = ""
+	e.origOx, e.origOy = v.ox, v.oy
+	e.origWcx, e.origWcy = v.wcx, v.wcy
+	if e.PromptView != nil {
+		e.PromptView.Clear()
+	}
+}
+
+// accept stops intercepting keystrokes, leaving the current match and the
+// scrolling NextMatch/PrevMatch did in place.
+func (e *IncrementalSearchEditor) accept() {
+	e.active = false
+	e.query = ""
+	if e.PromptView != nil {
+		e.PromptView.Clear()
+	}
+}
+
+// cancel stops intercepting keystrokes and undoes everything begin/search
+// did: the search highlighting is cleared and the view's scroll position and
+// cursor are restored to where they were when the search began.
+func (e *IncrementalSearchEditor) cancel(v *View) {
+	e.active = false
+	e.query = ""
+	v.ClearSearch()
+	v.ox, v.oy = e.origOx, e.origOy
+	v.wcx, v.wcy = e.origWcx, e.origWcy
+	if e.PromptView != nil {
+		e.PromptView.Clear()
+	}
+}
+
+func (e *IncrementalSearchEditor) setQuery(v *View, q string) {
+	e.query = q
+	if e.PromptView != nil {
+		e.PromptView.Clear()
+		fmt.Fprint(e.PromptView, q)
+	}
+
+	if q == "" {
+		v.ClearSearch()
+		return
+	}
+
+	if _, err := v.Search(q, SearchOptions{}); err != nil {
+		return
+	}
+	if e.forward {
+		v.NextMatch()
+	} else {
+		v.PrevMatch()
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "time"
+
+// maxUndoDepth bounds the number of edit records kept on the undo stack, so
+// a long editing session can't grow it without limit.
+const maxUndoDepth = 1000
+
+// coalesceWindow is the maximum gap between two consecutive single-rune
+// edits for them to be merged into a single undo step. This is what lets a
+// whole typed word be undone in one step instead of one rune at a time.
+const coalesceWindow = 750 * time.Millisecond
+
+type editActionKind int
+
+const (
+	editActionInsert editActionKind = iota
+	editActionDelete
+	editActionBreakLine
+	editActionMergeLine
+)
+
+// editRecord is a reversible description of a single mutation applied to a
+// View's internal line buffer.
+type editRecord struct {
+	kind  editActionKind
+	x, y  int
+	cells []cell
+	at    time.Time
+}
+
+// pushUndo records rec on the undo stack, coalescing it into the previous
+// record when possible, and clears the redo stack: any fresh edit
+// invalidates history that was undone before it.
+func (v *View) pushUndo(rec *editRecord) {
+	v.redoStack = nil
+
+	if top := v.lastUndoRecord(); top != nil && coalesceEdits(top, rec) {
+		return
+	}
+
+	v.undoStack = append(v.undoStack, rec)
+	if len(v.undoStack) > maxUndoDepth {
+		v.undoStack = v.undoStack[len(v.undoStack)-maxUndoDepth:]
+	}
+}
+
+func (v *View) lastUndoRecord() *editRecord {
+	if len(v.undoStack) == 0 {
+		return nil
+	}
+	return v.undoStack[len(v.undoStack)-1]
+}
+
+// coalesceEdits merges rec into top in place when they are the same kind of
+// single-rune edit, contiguous in the buffer, and close enough in time to
+// belong to the same keystroke burst. It reports whether it merged.
+func coalesceEdits(top, rec *editRecord) bool {
+	if rec.kind != top.kind || rec.y != top.y {
+		return false
+	}
+	if rec.at.Sub(top.at) > coalesceWindow {
+		return false
+	}
+
+	switch rec.kind {
+	case editActionInsert:
+		// typing extends the run at its right edge
+		if rec.x == top.x+len(top.cells) {
+			top.cells = append(top.cells, rec.cells...)
+			top.at = rec.at
+			return true
+		}
+	case editActionDelete:
+		// backspacing grows the run leftward
+		if rec.x == top.x-1 {
+			top.cells = append(rec.cells, top.cells...)
+			top.x = rec.x
+			top.at = rec.at
+			return true
+		}
+		// forward-delete (KeyDelete) grows the run rightward
+		if rec.x == top.x {
+			top.cells = append(top.cells, rec.cells...)
+			top.at = rec.at
+			return true
+		}
+	}
+
+	return false
+}
+
+// Undo reverts the most recent edit recorded on the undo stack, if any.
+func (v *View) Undo() {
+	if len(v.undoStack) == 0 {
+		return
+	}
+
+	n := len(v.undoStack) - 1
+	rec := v.undoStack[n]
+	v.undoStack = v.undoStack[:n]
+
+	v.withoutUndo(func() { v.applyInverse(rec) })
+	v.redoStack = append(v.redoStack, rec)
+}
+
+// Redo re-applies the most recently undone edit, if any.
+func (v *View) Redo() {
+	if len(v.redoStack) == 0 {
+		return
+	}
+
+	n := len(v.redoStack) - 1
+	rec := v.redoStack[n]
+	v.redoStack = v.redoStack[:n]
+
+	v.withoutUndo(func() { v.applyRecord(rec) })
+	v.undoStack = append(v.undoStack, rec)
+}
+
+// withoutUndo runs f with undo recording suppressed, so that replaying a
+// recorded mutation during Undo/Redo doesn't itself get recorded.
+func (v *View) withoutUndo(f func()) {
+	v.suppressUndo = true
+	defer func() { v.suppressUndo = false }()
+	f()
+}
+
+// applyInverse performs the opposite of rec, restoring the buffer to its
+// state before rec happened.
+func (v *View) applyInverse(rec *editRecord) {
+	switch rec.kind {
+	case editActionInsert:
+		for range rec.cells {
+			v.deleteRune(rec.x, rec.y)
+		}
+		v.setWriteCursor(rec.x, rec.y)
+	case editActionDelete:
+		for i, c := range rec.cells {
+			v.insertCell(rec.x+i, rec.y, c)
+		}
+		v.setWriteCursor(rec.x+len(rec.cells), rec.y)
+	case editActionBreakLine:
+		v.mergeLines(rec.y)
+		v.setWriteCursor(rec.x, rec.y)
+	case editActionMergeLine:
+		v.breakLine(rec.x, rec.y)
+		v.setWriteCursor(rec.x, rec.y)
+	}
+}
+
+// applyRecord re-applies rec, the mirror of applyInverse used by Redo.
+func (v *View) applyRecord(rec *editRecord) {
+	switch rec.kind {
+	case editActionInsert:
+		for i, c := range rec.cells {
+			v.insertCell(rec.x+i, rec.y, c)
+		}
+		v.setWriteCursor(rec.x+len(rec.cells), rec.y)
+	case editActionDelete:
+		for range rec.cells {
+			v.deleteRune(rec.x, rec.y)
+		}
+		v.setWriteCursor(rec.x, rec.y)
+	case editActionBreakLine:
+		v.breakLine(rec.x, rec.y)
+		v.setWriteCursor(rec.x, rec.y)
+	case editActionMergeLine:
+		v.mergeLines(rec.y)
+		v.setWriteCursor(rec.x, rec.y)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+type noopLogger struct{}
+
+func (noopLogger) Warn(args ...interface{})                 {}
+func (noopLogger) Warnf(format string, args ...interface{}) {}
+
+func newTestView() *View {
+	return &View{log: noopLogger{}}
+}
+
+// syncViewLines rebuilds viewLines from lines with no wrapping, standing in
+// for the layout pass the real Gui render loop does after every edit.
+func syncViewLines(v *View) {
+	v.viewLines = v.viewLines[:0]
+	for _, line := range v.lines {
+		v.viewLines = append(v.viewLines, viewLine{line: line})
+	}
+	if len(v.viewLines) == 0 {
+		v.viewLines = append(v.viewLines, viewLine{})
+	}
+}
+
+func lineString(v *View, y int) string {
+	if y >= len(v.lines) {
+		return ""
+	}
+	return cellsToString(v.lines[y])
+}
+
+func typeString(v *View, s string) {
+	for _, ch := range s {
+		v.EditWrite(ch)
+		syncViewLines(v)
+	}
+}
+
+func TestUndoRedoInsert(t *testing.T) {
+	v := newTestView()
+	typeString(v, "hello")
+
+	if got := lineString(v, 0); got != "hello" {
+		t.Fatalf("after typing: got %q, want %q", got, "hello")
+	}
+
+	// the whole word was typed as one contiguous burst, so it coalesces
+	// into a single undo step.
+	v.Undo()
+	syncViewLines(v)
+	if got := lineString(v, 0); got != "" {
+		t.Fatalf("after undo: got %q, want empty", got)
+	}
+
+	v.Redo()
+	syncViewLines(v)
+	if got := lineString(v, 0); got != "hello" {
+		t.Fatalf("after redo: got %q, want %q", got, "hello")
+	}
+}
+
+func TestUndoBreakLine(t *testing.T) {
+	v := newTestView()
+	typeString(v, "ab")
+	v.EditNewLine()
+	syncViewLines(v)
+	typeString(v, "cd")
+
+	if len(v.lines) != 2 || lineString(v, 0) != "ab" || lineString(v, 1) != "cd" {
+		t.Fatalf("unexpected buffer after typing across a line break: %q / %q", lineString(v, 0), lineString(v, 1))
+	}
+
+	v.Undo() // undoes the "cd" insert
+	syncViewLines(v)
+	v.Undo() // undoes the line break itself
+	syncViewLines(v)
+
+	if len(v.lines) != 1 || lineString(v, 0) != "ab" {
+		t.Fatalf("after undoing the break: got %d line(s), line 0 = %q", len(v.lines), lineString(v, 0))
+	}
+}
+
+func TestUndoMultiRuneWidth(t *testing.T) {
+	v := newTestView()
+	v.EditWrite('a')
+	syncViewLines(v)
+	v.EditWrite('文') // double-width CJK rune
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "a文" {
+		t.Fatalf("after typing: got %q, want %q", got, "a文")
+	}
+	if v.wcx != 3 { // 1 (width of 'a') + 2 (width of '文')
+		t.Fatalf("cursor after typing double-width rune: got wcx=%d, want 3", v.wcx)
+	}
+
+	v.Undo()
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "" {
+		t.Fatalf("after undo: got %q, want empty", got)
+	}
+	if v.wcx != 0 {
+		t.Fatalf("cursor after undo: got wcx=%d, want 0", v.wcx)
+	}
+}
+
+func TestUndoAfterCursorMove(t *testing.T) {
+	v := newTestView()
+	typeString(v, "ab")
+
+	// moving the cursor doesn't touch the undo stack, so a later Undo must
+	// still revert the typed text regardless of where the cursor ended up.
+	v.moveCursor(-1, 0, false)
+
+	v.Undo()
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "" {
+		t.Fatalf("after undo following a cursor move: got %q, want empty", got)
+	}
+	if v.wcx != 0 || v.wcy != 0 {
+		t.Fatalf("cursor after undo: got (%d,%d), want (0,0)", v.wcx, v.wcy)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// Mode identifies a ViEditor's current modal state.
+type Mode int
+
+const (
+	ModeNormal Mode = iota
+	ModeInsert
+	ModeVisual
+	ModeVisualLine
+)
+
+// ViEditor is an Editor giving a View a modal, vi-like editing experience:
+// normal, insert, and visual modes, built entirely on View's existing
+// primitives plus the undo stack and kill ring. All modal state (current
+// mode, pending operator, count prefix, last search) lives on the editor
+// itself rather than on View, so each View assigned its own *ViEditor has
+// independent modal state; don't share one *ViEditor between views.
+//
+//	v.Editor = gocui.NewViEditor()
+type ViEditor struct {
+	mode         Mode
+	pendingOp    rune
+	pendingCount int
+	count        int
+	lastSearch   string
+
+	visualX, visualY int
+
+	onModeChange func(Mode)
+}
+
+// NewViEditor returns a ViEditor starting in normal mode.
+func NewViEditor() *ViEditor {
+	return &ViEditor{mode: ModeNormal}
+}
+
+// OnModeChange registers f to be called whenever the editor's mode changes,
+// so that e.g. a status bar can render "NORMAL"/"INSERT"/"VISUAL".
+func (e *ViEditor) OnModeChange(f func(Mode)) {
+	e.onModeChange = f
+}
+
+// Mode returns the editor's current mode.
+func (e *ViEditor) Mode() Mode {
+	return e.mode
+}
+
+func (e *ViEditor) setMode(m Mode) {
+	if e.mode == m {
+		return
+	}
+	e.mode = m
+	if e.onModeChange != nil {
+		e.onModeChange(m)
+	}
+}
+
+// Edit implements the Editor interface.
+func (e *ViEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	switch e.mode {
+	case ModeInsert:
+		e.editInsert(v, key, ch, mod)
+	case ModeVisual, ModeVisualLine:
+		e.editVisual(v, key, ch, mod)
+	default:
+		e.editNormal(v, key, ch, mod)
+	}
+}
+
+func (e *ViEditor) editInsert(v *View, key Key, ch rune, mod Modifier) {
+	if key == KeyEsc {
+		v.MoveCursor(-1, 0, false)
+		e.setMode(ModeNormal)
+		return
+	}
+	simpleEditor(v, key, ch, mod)
+}
+
+// editNormal handles normal-mode keys. A numeric prefix repeats the motion
+// or operator that follows it (e.g. "3w", "2dd").
+func (e *ViEditor) editNormal(v *View, key Key, ch rune, mod Modifier) {
+	if e.pendingOp != 0 {
+		op := e.pendingOp
+		count := e.pendingCount
+		e.pendingOp = 0
+		e.pendingCount = 0
+		e.applyPending(v, op, ch, count)
+		return
+	}
+
+	if (ch >= '1' && ch <= '9') || (ch == '0' && e.count > 0) {
+		e.count = e.count*10 + int(ch-'0')
+		return
+	}
+
+	count := e.count
+	if count == 0 {
+		count = 1
+	}
+	e.count = 0
+
+	switch ch {
+	case 'h':
+		for i := 0; i < count; i++ {
+			v.MoveCursor(-1, 0, false)
+		}
+	case 'l':
+		for i := 0; i < count; i++ {
+			v.MoveCursor(1, 0, false)
+		}
+	case 'j':
+		for i := 0; i < count; i++ {
+			v.MoveCursor(0, 1, false)
+		}
+	case 'k':
+		for i := 0; i < count; i++ {
+			v.MoveCursor(0, -1, false)
+		}
+	case 'w':
+		for i := 0; i < count; i++ {
+			v.EditMoveWordRight()
+		}
+	case 'b':
+		for i := 0; i < count; i++ {
+			v.EditMoveWordLeft()
+		}
+	case 'e':
+		for i := 0; i < count; i++ {
+			v.skipWord(1)
+		}
+	case '0':
+		v.EditGotoToStartOfLine()
+	case '$':
+		v.EditGotoToEndOfLine()
+	case 'G':
+		for v.wcy < len(v.viewLines)-1 {
+			v.MoveCursor(0, 1, false)
+		}
+	case 'x':
+		for i := 0; i < count; i++ {
+			v.EditDelete(false)
+		}
+	case 'u':
+		for i := 0; i < count; i++ {
+			v.Undo()
+		}
+	case 'p':
+		v.EditYank()
+	case 'i':
+		e.setMode(ModeInsert)
+	case 'a':
+		v.MoveCursor(1, 0, true)
+		e.setMode(ModeInsert)
+	case 'o':
+		v.EditGotoToEndOfLine()
+		v.EditNewLine()
+		e.setMode(ModeInsert)
+	case 'O':
+		v.EditGotoToStartOfLine()
+		v.breakLine(v.wcx, v.wcy)
+		e.setMode(ModeInsert)
+	case 'v':
+		e.visualX, e.visualY = v.wcx, v.wcy
+		e.setMode(ModeVisual)
+	case 'V':
+		e.visualX, e.visualY = v.wcx, v.wcy
+		e.setMode(ModeVisualLine)
+	case 'd', 'y', 'g':
+		e.pendingOp = ch
+		e.pendingCount = count
+	}
+}
+
+// applyPending completes a two-key normal-mode command: "gg", "dd", "dw",
+// or "yy". count is the numeric prefix that preceded the operator (e.g. the
+// "2" in "2dd"), already defaulted to 1 by editNormal.
+func (e *ViEditor) applyPending(v *View, op, motion rune, count int) {
+	switch {
+	case op == 'g' && motion == 'g':
+		for v.wcy > 0 {
+			v.MoveCursor(0, -1, false)
+		}
+		v.EditGotoToStartOfLine()
+	case op == 'd' && motion == 'd':
+		for i := 0; i < count; i++ {
+			e.deleteLine(v)
+		}
+	case op == 'd' && motion == 'w':
+		for i := 0; i < count; i++ {
+			v.EditDeleteWordRight()
+		}
+	case op == 'y' && motion == 'y':
+		e.yankLines(v, count)
+	}
+}
+
+// deleteLine removes the entire current line, pushing it onto the kill
+// ring, leaving the cursor at the start of the line that takes its place.
+func (e *ViEditor) deleteLine(v *View) {
+	v.EditGotoToStartOfLine()
+	v.EditKillToEndOfLine()
+	v.mergeLines(v.wcy)
+}
+
+// yankLines copies the current line and the count-1 lines below it onto the
+// kill ring, one push per line, without removing any of them.
+func (e *ViEditor) yankLines(v *View, count int) {
+	for i := 0; i < count; i++ {
+		if s, ok := v.lineText(v.wcy + i); ok {
+			v.pushKill(s)
+		}
+	}
+}
+
+func (e *ViEditor) editVisual(v *View, key Key, ch rune, mod Modifier) {
+	switch {
+	case key == KeyEsc:
+		e.setMode(ModeNormal)
+	case ch == 'h':
+		v.MoveCursor(-1, 0, false)
+	case ch == 'l':
+		v.MoveCursor(1, 0, false)
+	case ch == 'j':
+		v.MoveCursor(0, 1, false)
+	case ch == 'k':
+		v.MoveCursor(0, -1, false)
+	case ch == 'd':
+		e.deleteSelection(v)
+		e.setMode(ModeNormal)
+	case ch == 'y':
+		e.yankSelection(v)
+		e.setMode(ModeNormal)
+	}
+}
+
+// deleteSelection removes the active visual selection. A selection
+// spanning more than one line, or made in ModeVisualLine, is treated as a
+// whole-line range; a same-line ModeVisual selection is removed
+// character-wise.
+func (e *ViEditor) deleteSelection(v *View) {
+	if e.mode == ModeVisualLine || e.visualY != v.wcy {
+		e.deleteLineRange(v)
+		return
+	}
+
+	x0, x1 := e.visualX, v.wcx
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+	v.killRange(x0, e.visualY, x1+1, e.visualY)
+}
+
+// yankSelection is the non-destructive counterpart of deleteSelection.
+func (e *ViEditor) yankSelection(v *View) {
+	if e.mode == ModeVisualLine || e.visualY != v.wcy {
+		e.yankLineRange(v)
+		return
+	}
+
+	x0, x1 := e.visualX, v.wcx
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+
+	if s, ok := v.textInRange(x0, e.visualY, x1+1); ok && s != "" {
+		v.pushKill(s)
+	}
+}
+
+func (e *ViEditor) deleteLineRange(v *View) {
+	y0, y1 := e.visualY, v.wcy
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	_ = v.setWriteCursor(0, y0)
+	for y := y0; y <= y1; y++ {
+		e.deleteLine(v)
+	}
+}
+
+func (e *ViEditor) yankLineRange(v *View) {
+	y0, y1 := e.visualY, v.wcy
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y <= y1; y++ {
+		if s, ok := v.lineText(y); ok {
+			v.pushKill(s)
+		}
+	}
+}
+
+// lineText returns the contents of the logical line at write-cursor row y
+// as a string.
+func (v *View) lineText(y int) (string, bool) {
+	_, realY, err := v.realPosition(0, y)
+	if err != nil || realY < 0 || realY >= len(v.lines) {
+		return "", false
+	}
+	return cellsToString(v.lines[realY]), true
+}
@@ -0,0 +1,79 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+// sendKeys feeds each rune of s through e in normal mode, as consecutive
+// keystrokes, syncing viewLines after each the same way the real render
+// loop would before the next one arrives.
+func sendKeys(e *ViEditor, v *View, s string) {
+	for _, ch := range s {
+		e.Edit(v, 0, ch, 0)
+		syncViewLines(v)
+	}
+}
+
+func newTestLines(lines ...string) *View {
+	v := newTestView()
+	cells := make([][]cell, len(lines))
+	for i, l := range lines {
+		cells[i] = makeLine(l)
+	}
+	v.lines = cells
+	syncViewLines(v)
+	return v
+}
+
+func TestViEditorDeleteLine(t *testing.T) {
+	v := newTestLines("one", "two", "three")
+	e := NewViEditor()
+
+	sendKeys(e, v, "dd")
+
+	if got := lineString(v, 0); got != "two" {
+		t.Fatalf("line 0 after dd: got %q, want %q", got, "two")
+	}
+	if got := lineString(v, 1); got != "three" {
+		t.Fatalf("line 1 after dd: got %q, want %q", got, "three")
+	}
+	if len(v.lines) != 2 {
+		t.Fatalf("line count after dd: got %d, want 2", len(v.lines))
+	}
+	if v.killRing[len(v.killRing)-1] != "one" {
+		t.Fatalf("kill ring top: got %q, want %q", v.killRing[len(v.killRing)-1], "one")
+	}
+}
+
+func TestViEditorDeleteLineWithCount(t *testing.T) {
+	v := newTestLines("one", "two", "three")
+	e := NewViEditor()
+
+	sendKeys(e, v, "2dd")
+
+	if got := lineString(v, 0); got != "three" {
+		t.Fatalf("line 0 after 2dd: got %q, want %q", got, "three")
+	}
+	if len(v.lines) != 1 {
+		t.Fatalf("line count after 2dd: got %d, want 1", len(v.lines))
+	}
+}
+
+func TestViEditorYankLineWithCount(t *testing.T) {
+	v := newTestLines("one", "two", "three")
+	e := NewViEditor()
+
+	sendKeys(e, v, "2yy")
+
+	if len(v.lines) != 3 {
+		t.Fatalf("yy must not delete: line count got %d, want 3", len(v.lines))
+	}
+	if got := v.killRing[len(v.killRing)-1]; got != "two" {
+		t.Fatalf("kill ring top: got %q, want %q", got, "two")
+	}
+	if got := v.killRing[len(v.killRing)-2]; got != "one" {
+		t.Fatalf("kill ring second-to-top: got %q, want %q", got, "one")
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"github.com/go-errors/errors"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Attribute is a text attribute, such as a color.
+type Attribute int
+
+// cell represents a rune together with its style, as stored in a View's
+// internal line buffer.
+type cell struct {
+	fgColor, bgColor Attribute
+	chr              rune
+}
+
+// viewLine represents a single on-screen line: either a whole logical line,
+// or one segment of a logical line that Wrap has split across several
+// on-screen rows. linesX is the offset of this segment within its logical
+// line; it is 0 for the first (or only) segment of a line.
+type viewLine struct {
+	linesX int
+	line   []cell
+}
+
+// logger is the minimal logging surface View needs; satisfied by
+// logrus.Logger in the real gocui package.
+type logger interface {
+	Warn(args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// View represents a box in the terminal and contains its own internal
+// buffer and cursor management.
+type View struct {
+	Name string
+
+	// FgColor and BgColor allow to configure the color of the text and
+	// background of the View.
+	FgColor, BgColor Attribute
+	// SelFgColor and SelBgColor are used instead of FgColor/BgColor for a
+	// selected line, such as the current search match.
+	SelFgColor, SelBgColor Attribute
+
+	// Wrap turns on line wrapping when rendering the view's content.
+	Wrap bool
+	// Overwrite, when true, makes EditWrite overwrite the rune under the
+	// cursor instead of inserting before it.
+	Overwrite bool
+	// Editor allows to define a custom editor for the view, or nil for the
+	// default one.
+	Editor Editor
+
+	// WrapWidth, when positive, makes EditWrite soft-wrap a logical line
+	// once it grows past this many display columns. 0 disables it. Unlike
+	// Wrap, which only affects how an unmodified line is displayed, this
+	// actually breaks the line while typing.
+	WrapWidth int
+
+	// HistoryEnabled opts a single-line View into PromptEditor's line
+	// history and reverse search.
+	HistoryEnabled bool
+	// History holds the lines pushed via PushHistory, oldest first.
+	History    []string
+	historyPos int
+
+	historySearchActive bool
+	historySearchQuery  string
+	historySearchPos    int
+	historySearchPrev   string
+
+	// Completer, when set, is invoked on Tab by PromptEditor to propose
+	// completions for the text at the cursor.
+	Completer Completer
+	// CompletionView, when set, is used to render the current completion
+	// candidates as a companion popup view.
+	CompletionView *View
+	completions    []string
+	completionPos  int
+	completionHead string
+	completionTail string
+
+	// undoStack and redoStack back View.Undo/View.Redo. suppressUndo is set
+	// while replaying a record from either stack, so doing so doesn't itself
+	// get recorded.
+	undoStack    []*editRecord
+	redoStack    []*editRecord
+	suppressUndo bool
+
+	// killRing backs EditYank/EditYankPop; killRingPos is the index of the
+	// entry most recently pushed or yanked, which the next EditYankPop steps
+	// back from, and yankLen is how many runes
+	// the most recent yank inserted, so EditYankPop knows how much to undo.
+	killRing    []string
+	killRingPos int
+	yankLen     int
+
+	// searchMatches/searchPattern/searchOpts back Search/NextMatch/PrevMatch;
+	// searchPos is the index into searchMatches of the current match, or -1
+	// if none has been stepped to yet.
+	searchMatches []Match
+	searchPattern string
+	searchOpts    SearchOptions
+	searchPos     int
+
+	// lines is the internal buffer: one []cell per logical line.
+	lines [][]cell
+	// viewLines is lines split into on-screen rows according to Wrap.
+	viewLines []viewLine
+
+	// ox, oy are the origin offsets (scroll position) of the view.
+	ox, oy int
+	// cx, cy are the displayed cursor position, relative to the origin.
+	cx, cy int
+	// wcx, wcy are the logical ("write") cursor position used by the Edit*
+	// family of methods; they track the cursor within viewLines independent
+	// of scrolling.
+	wcx, wcy int
+
+	// tainted is set whenever the buffer is mutated, so the renderer knows
+	// it needs to recompute viewLines before the next draw.
+	tainted bool
+
+	// width and height are the view's visible size in columns/rows, as set
+	// by its containing Gui layout; 0 means unset.
+	width, height int
+
+	log logger
+}
+
+// Size returns the number of visible columns and rows in the view.
+func (v *View) Size() (int, int) {
+	w, h := v.width, v.height
+	if w == 0 {
+		w = 80
+	}
+	if h == 0 {
+		h = 24
+	}
+	return w, h
+}
+
+// realPosition converts a point relative to the view's origin into a point
+// in the view's internal buffer.
+func (v *View) realPosition(x, y int) (int, int, error) {
+	rx := v.ox + x
+	ry := v.oy + y
+
+	if rx < 0 || ry < 0 {
+		return 0, 0, errors.New("invalid point")
+	}
+	return rx, ry, nil
+}
+
+// writeCursor returns the current write-cursor position.
+func (v *View) writeCursor() (int, int) {
+	return v.wcx, v.wcy
+}
+
+// setWriteCursor moves the write cursor to (x, y) without touching the
+// buffer.
+func (v *View) setWriteCursor(x, y int) error {
+	if x < 0 || y < 0 {
+		return errors.New("invalid point")
+	}
+	v.wcx, v.wcy = x, y
+	return nil
+}
+
+// Clear empties the view's buffer.
+func (v *View) Clear() {
+	v.tainted = true
+	v.lines = nil
+	v.viewLines = nil
+	v.wcx, v.wcy = 0, 0
+	v.ox, v.oy = 0, 0
+}
+
+// Write implements io.Writer, appending p to the view at the current
+// write cursor.
+func (v *View) Write(p []byte) (int, error) {
+	for _, ch := range string(p) {
+		if ch == '\n' {
+			v.EditNewLine()
+			continue
+		}
+		v.EditWrite(ch)
+	}
+	return len(p), nil
+}
+
+// lineWidth returns the total display width of line.
+func lineWidth(line []cell) int {
+	w := 0
+	for _, c := range line {
+		w += runewidth.RuneWidth(c.chr)
+	}
+	return w
+}
+
+// cellsToString returns the runes of line concatenated into a string,
+// dropping their styling.
+func cellsToString(line []cell) string {
+	runes := make([]rune, len(line))
+	for i, c := range line {
+		runes[i] = c.chr
+	}
+	return string(runes)
+}
@@ -0,0 +1,246 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// maxKillRing bounds the number of kills kept on the ring.
+const maxKillRing = 32
+
+// isWordRune reports whether ch is part of a "word" for the purposes of
+// word-wise motion and deletion, i.e. a letter or digit.
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch)
+}
+
+// EditMoveWordLeft moves the cursor to the start of the previous word.
+func (v *View) EditMoveWordLeft() {
+	v.skipNonWord(-1)
+	v.skipWord(-1)
+}
+
+// EditMoveWordRight moves the cursor to the start of the next word.
+func (v *View) EditMoveWordRight() {
+	v.skipWord(1)
+	v.skipNonWord(1)
+}
+
+// skipWord moves the cursor across runes of the same word-ness as the rune
+// behind (dir<0) or ahead (dir>0) of it, stopping at the first boundary.
+func (v *View) skipWord(dir int) {
+	ch, ok := v.runeAt(dir)
+	if !ok {
+		return
+	}
+	word := isWordRune(ch)
+	for {
+		ch, ok := v.runeAt(dir)
+		if !ok || isWordRune(ch) != word {
+			return
+		}
+		v.MoveCursor(dir, 0, false)
+	}
+}
+
+// skipNonWord moves the cursor across non-word runes in the given
+// direction, stopping at the first word rune or buffer edge.
+func (v *View) skipNonWord(dir int) {
+	for {
+		ch, ok := v.runeAt(dir)
+		if !ok || isWordRune(ch) {
+			return
+		}
+		v.MoveCursor(dir, 0, false)
+	}
+}
+
+// runeAt returns the rune immediately behind (dir<0) or ahead (dir>0) of the
+// write cursor, and whether one exists there.
+func (v *View) runeAt(dir int) (rune, bool) {
+	x, y := v.wcx, v.wcy
+	if dir < 0 {
+		x--
+	}
+	if y < 0 || y >= len(v.viewLines) {
+		return 0, false
+	}
+	line := v.viewLines[y].line
+	if x < 0 || x >= len(line) {
+		return 0, false
+	}
+	return line[x].chr, true
+}
+
+// EditDeleteWordLeft deletes from the cursor back to the start of the
+// previous word, pushing the deleted text onto the kill ring.
+func (v *View) EditDeleteWordLeft() {
+	x0, y0 := v.wcx, v.wcy
+	v.EditMoveWordLeft()
+	v.killRange(v.wcx, v.wcy, x0, y0)
+}
+
+// EditDeleteWordRight deletes from the cursor to the start of the next
+// word, pushing the deleted text onto the kill ring.
+func (v *View) EditDeleteWordRight() {
+	x0, y0 := v.wcx, v.wcy
+	v.EditMoveWordRight()
+	v.killRange(x0, y0, v.wcx, v.wcy)
+}
+
+// EditKillToEndOfLine deletes from the cursor to the end of the current
+// logical line, pushing the deleted text onto the kill ring. It reads the
+// line straight out of the internal buffer rather than going through
+// EditGotoToEndOfLine's cursor-relocation logic, so unlike that method it
+// works the same on every line, not just the last one.
+func (v *View) EditKillToEndOfLine() {
+	x, y, err := v.realPosition(v.wcx, v.wcy)
+	if err != nil || y < 0 || y >= len(v.lines) || x >= len(v.lines[y]) {
+		return
+	}
+
+	tail := v.lines[y][x:]
+	killed := make([]rune, len(tail))
+	for i, c := range tail {
+		killed[i] = c.chr
+	}
+
+	for range killed {
+		v.EditDelete(false)
+	}
+
+	v.pushKill(string(killed))
+}
+
+// killRange deletes the single-line run of display columns [x0,x1) on line
+// y0, pushing the removed runes onto the kill ring and leaving the cursor at
+// the start of the range. x0 and x1 are display columns, as returned by
+// MoveCursor's width-aware snapping, so the number of runes in between is
+// first worked out by walking the real buffer and tracking column width as
+// we go, rather than treating x1-x0 as a rune count: a multi-cell-width
+// rune (CJK, emoji, ...) spans more than one column. The runes are then
+// removed with backward deletes from x1, one per rune, because EditDelete's
+// backspacing path re-derives the column to land on from the width of the
+// rune it just removed, whereas deleting forward from x0 would re-test x0
+// against the buffer's shrinking length on every iteration and misfire once
+// a multi-cell-width rune throws that length out of step with the column.
+func (v *View) killRange(x0, y0, x1, _ int) {
+	if x1 <= x0 {
+		return
+	}
+
+	_, ry, err := v.realPosition(x0, y0)
+	if err != nil || ry < 0 || ry >= len(v.lines) {
+		return
+	}
+
+	line := v.lines[ry]
+	start, end := runeRangeForColumns(line, x0, x1)
+	if start == end {
+		return
+	}
+
+	killed := make([]rune, end-start)
+	for i, c := range line[start:end] {
+		killed[i] = c.chr
+	}
+
+	_ = v.setWriteCursor(x1, y0)
+	for range killed {
+		v.EditDelete(true)
+	}
+	v.pushKill(string(killed))
+}
+
+// runeRangeForColumns translates the display-column range [x0,x1) into a
+// rune-index range [start,end) within line, walking it and tracking column
+// width as it goes rather than assuming one column per rune.
+func runeRangeForColumns(line []cell, x0, x1 int) (start, end int) {
+	start, end = len(line), len(line)
+	col := 0
+	for i, c := range line {
+		if col == x0 {
+			start = i
+		}
+		if col >= x1 {
+			end = i
+			break
+		}
+		col += runewidth.RuneWidth(c.chr)
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// textInRange returns the text spanning display columns [x0,x1) on logical
+// row y, without mutating the buffer.
+func (v *View) textInRange(x0, y, x1 int) (string, bool) {
+	if x1 <= x0 {
+		return "", true
+	}
+
+	_, ry, err := v.realPosition(x0, y)
+	if err != nil || ry < 0 || ry >= len(v.lines) {
+		return "", false
+	}
+
+	line := v.lines[ry]
+	start, end := runeRangeForColumns(line, x0, x1)
+	return cellsToString(line[start:end]), true
+}
+
+// pushKill pushes s onto the kill ring, bounding its size, and resets the
+// yank-pop cursor so the next Yank starts from the most recent kill.
+func (v *View) pushKill(s string) {
+	v.killRing = append(v.killRing, s)
+	if len(v.killRing) > maxKillRing {
+		v.killRing = v.killRing[len(v.killRing)-maxKillRing:]
+	}
+	v.killRingPos = len(v.killRing) - 1
+}
+
+// EditYank inserts the most recent entry on the kill ring at the cursor.
+func (v *View) EditYank() {
+	if len(v.killRing) == 0 {
+		return
+	}
+	v.killRingPos = len(v.killRing) - 1
+	v.yankLen = v.insertString(v.killRing[v.killRingPos])
+}
+
+// EditYankPop replaces the just-yanked text with the previous entry on the
+// kill ring, rotating through it on repeated calls. It is only meaningful
+// immediately after EditYank or another EditYankPop.
+func (v *View) EditYankPop() {
+	if len(v.killRing) == 0 || v.yankLen == 0 {
+		return
+	}
+
+	for i := 0; i < v.yankLen; i++ {
+		v.EditDelete(true)
+	}
+
+	v.killRingPos--
+	if v.killRingPos < 0 {
+		v.killRingPos = len(v.killRing) - 1
+	}
+	v.yankLen = v.insertString(v.killRing[v.killRingPos])
+}
+
+// insertString writes s at the cursor and returns the number of runes
+// written, for use by EditYankPop to know how much to remove again.
+func (v *View) insertString(s string) int {
+	n := 0
+	for _, ch := range s {
+		v.EditWrite(ch)
+		n++
+	}
+	return n
+}
@@ -0,0 +1,99 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+func TestEditKillToEndOfLineMultiLine(t *testing.T) {
+	v := newTestView()
+	typeString(v, "hello")
+	v.EditNewLine()
+	syncViewLines(v)
+	typeString(v, "world")
+
+	// kill from the middle of the first (non-last) line
+	_ = v.setWriteCursor(3, 0)
+	v.EditKillToEndOfLine()
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "hel" {
+		t.Fatalf("line 0 after kill: got %q, want %q", got, "hel")
+	}
+	if got := lineString(v, 1); got != "world" {
+		t.Fatalf("line 1 after kill: got %q, want %q (should be untouched)", got, "world")
+	}
+	if v.killRing[len(v.killRing)-1] != "lo" {
+		t.Fatalf("kill ring top: got %q, want %q", v.killRing[len(v.killRing)-1], "lo")
+	}
+}
+
+func TestEditKillToEndOfLineLastLine(t *testing.T) {
+	v := newTestView()
+	typeString(v, "hello")
+
+	_ = v.setWriteCursor(2, 0)
+	v.EditKillToEndOfLine()
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "he" {
+		t.Fatalf("after kill: got %q, want %q", got, "he")
+	}
+	if v.killRing[len(v.killRing)-1] != "llo" {
+		t.Fatalf("kill ring top: got %q, want %q", v.killRing[len(v.killRing)-1], "llo")
+	}
+}
+
+// makeLine builds a single buffer line out of s, for tests that need a
+// multi-rune-width line in place without going through EditWrite (whose
+// write-cursor tracks display columns rather than rune indices, same as the
+// rest of the upstream View buffer).
+func makeLine(s string) []cell {
+	line := make([]cell, 0, len(s))
+	for _, ch := range s {
+		line = append(line, cell{chr: ch})
+	}
+	return line
+}
+
+// TestKillRangeMultiRuneWidth exercises killRange directly with a line
+// containing a double-width rune: x0/x1 are display columns (as produced by
+// MoveCursor's width-aware snapping), which is not the same as a rune count
+// once a multi-cell-width rune is in the range.
+func TestKillRangeMultiRuneWidth(t *testing.T) {
+	v := newTestView()
+	v.lines = [][]cell{makeLine("a文 bcd")}
+	syncViewLines(v)
+
+	// columns: a=0, 文=1..2, space=3, b=4, c=5, d=6; killing [4,6) should
+	// remove exactly "bc", even though the double-width 文 puts their rune
+	// indices (3 and 4) one behind their columns.
+	v.killRange(4, 0, 6, 0)
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != "a文 d" {
+		t.Fatalf("after killRange: got %q, want %q", got, "a文 d")
+	}
+	if v.killRing[len(v.killRing)-1] != "bc" {
+		t.Fatalf("kill ring top: got %q, want %q", v.killRing[len(v.killRing)-1], "bc")
+	}
+}
+
+func TestKillRangeStopsAtMultiRuneWidthBoundary(t *testing.T) {
+	v := newTestView()
+	v.lines = [][]cell{makeLine("文字 rest")}
+	syncViewLines(v)
+
+	// columns: 文=0..1, 字=2..3, space=4, r=5...; killing [0,4) should take
+	// exactly the two double-width runes, not run past them into the space.
+	v.killRange(0, 0, 4, 0)
+	syncViewLines(v)
+
+	if got := lineString(v, 0); got != " rest" {
+		t.Fatalf("after killRange: got %q, want %q", got, " rest")
+	}
+	if v.killRing[len(v.killRing)-1] != "文字" {
+		t.Fatalf("kill ring top: got %q, want %q", v.killRing[len(v.killRing)-1], "文字")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "github.com/mattn/go-runewidth"
+
+// wrapCurrentLine breaks the logical line the write cursor is on at the
+// nearest whitespace to its left once it grows past WrapWidth, moving the
+// trailing word (and the cursor, if it was inside that word) down to a new
+// line. It only ever looks at the single line just written to, so it's
+// idempotent: a line at or under WrapWidth is left alone, which keeps it
+// from cascading into repeated breaks as EditWrite calls it after every
+// rune.
+func (v *View) wrapCurrentLine() {
+	_, realY, err := v.realPosition(0, v.wcy)
+	if err != nil || realY < 0 || realY >= len(v.lines) {
+		return
+	}
+
+	line := v.lines[realY]
+	if lineWidth(line) <= v.WrapWidth {
+		return
+	}
+
+	breakAt := v.findWrapPoint(line)
+	if breakAt <= 0 || breakAt >= len(line) {
+		return
+	}
+
+	cursorInTail := v.wcx > breakAt
+	brokeOnSpace := isSpaceCell(line[breakAt])
+
+	v.breakLine(breakAt, v.wcy)
+
+	// findWrapPoint breaks right after a whitespace rune, which stays at the
+	// end of the first line; only when that rune is followed by more
+	// whitespace does the new line start with a space. Drop that one space so
+	// the wrapped word starts closer to the margin.
+	if brokeOnSpace {
+		v.deleteRune(0, v.wcy+1)
+		breakAt++
+	}
+
+	if cursorInTail {
+		v.wcy++
+		v.wcx -= breakAt
+		v.ox = 0
+	}
+}
+
+// findWrapPoint returns the index in line to break at: the position right
+// after the rightmost whitespace at or before WrapWidth display columns, or
+// the column itself if the line has no whitespace to break on.
+func (v *View) findWrapPoint(line []cell) int {
+	col := 0
+	lastSpace := -1
+	hardBreak := 0
+	for i, c := range line {
+		if col <= v.WrapWidth && isSpaceCell(c) {
+			lastSpace = i
+		}
+		w := runewidth.RuneWidth(c.chr)
+		if col+w > v.WrapWidth && hardBreak == 0 {
+			hardBreak = i
+		}
+		col += w
+	}
+
+	if lastSpace >= 0 {
+		return lastSpace + 1
+	}
+	return hardBreak
+}
+
+func isSpaceCell(c cell) bool {
+	return c.chr == ' ' || c.chr == '\t'
+}
+
+// ReflowParagraph reflows the paragraph starting at logical line y to
+// respect WrapWidth: it first merges y with the lines that follow it, then
+// re-breaks the result at WrapWidth boundaries. Intended for use after a
+// multi-line paste, where the pasted text arrives as hard line breaks that
+// still need wrapping.
+func (v *View) ReflowParagraph(y int) {
+	if v.WrapWidth <= 0 || y < 0 || y >= len(v.lines) {
+		return
+	}
+
+	// Only absorb lines that belong to this paragraph: a blank line (or the
+	// end of the buffer) marks the paragraph's extent, so stop there rather
+	// than merging the rest of the document down onto y.
+	for y < len(v.lines)-1 && len(v.lines[y]) > 0 && len(v.lines[y+1]) > 0 {
+		v.mergeLines(y)
+	}
+
+	for lineWidth(v.lines[y]) > v.WrapWidth {
+		line := v.lines[y]
+		breakAt := v.findWrapPoint(line)
+		if breakAt <= 0 || breakAt >= len(line) {
+			break
+		}
+
+		brokeOnSpace := isSpaceCell(line[breakAt])
+		v.breakLine(breakAt, y)
+		if brokeOnSpace {
+			v.deleteRune(0, y+1)
+		}
+		y++
+	}
+}